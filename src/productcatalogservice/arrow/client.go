@@ -0,0 +1,155 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package arrow
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+
+	arrowpb "github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/genproto/oteldemo/arrow"
+)
+
+// serviceName is what reflection is asked for to decide whether a peer
+// understands the Arrow transport at all.
+const serviceName = "oteldemo.arrow.ArrowStreamService"
+
+// reflectionTimeout bounds the one-off reflection call made per Dial so
+// a peer that never answers can't hang startup.
+const reflectionTimeout = 3 * time.Second
+
+// stream wraps a single BatchArrowRecords stream with an in-flight
+// counter so the pool can pick the least-loaded one. grpc's
+// ClientStream forbids calling Send (or Recv) from more than one
+// goroutine at a time on the same stream, so mu serializes the
+// send-then-recv pair against any other Send that picks this same
+// stream concurrently.
+type stream struct {
+	client   arrowpb.ArrowStreamService_BatchArrowRecordsClient
+	mu       sync.Mutex
+	inFlight atomic.Int64
+}
+
+// Client is a best-of-N stream prioritizer: it keeps N concurrent
+// BatchArrowRecords streams open to the same peer and sends each batch
+// down whichever stream currently has the fewest outstanding,
+// unacknowledged batches.
+type Client struct {
+	streams []*stream
+	log     *logrus.Logger
+	ins     *instruments
+}
+
+// Supported reports whether conn's peer advertises the Arrow transport
+// via gRPC server reflection. Callers use this to decide between
+// dialing an arrow.Client and falling back to plain OTLP unary calls.
+func Supported(ctx context.Context, conn *grpc.ClientConn) bool {
+	ctx, cancel := context.WithTimeout(ctx, reflectionTimeout)
+	defer cancel()
+
+	rc := grpc_reflection_v1.NewServerReflectionClient(conn)
+	stream, err := rc.ServerReflectionInfo(ctx)
+	if err != nil {
+		return false
+	}
+	defer stream.CloseSend()
+
+	if err := stream.Send(&grpc_reflection_v1.ServerReflectionRequest{
+		MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_FileContainingSymbol{
+			FileContainingSymbol: serviceName,
+		},
+	}); err != nil {
+		return false
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return false
+	}
+	_, ok := resp.MessageResponse.(*grpc_reflection_v1.ServerReflectionResponse_FileDescriptorResponse)
+	return ok
+}
+
+// RecordFallback increments arrow.client.fallbacks. Callers that decide
+// to use plain OTLP instead of the Arrow transport - because the peer
+// didn't advertise support, or because stream setup failed after it did
+// - should call this so that fallback rate is visible even though it
+// never shows up as an error on the request path itself.
+func RecordFallback(ctx context.Context) {
+	ins, err := newInstruments()
+	if err != nil {
+		return
+	}
+	ins.fallbackDials.Add(ctx, 1)
+}
+
+// Dial opens n concurrent BatchArrowRecords streams against conn. n is
+// typically small (2-4): enough to hide one slow stream's head-of-line
+// blocking without fragmenting batches across so many streams that each
+// one's Arrow dictionaries churn.
+func Dial(ctx context.Context, conn *grpc.ClientConn, n int, log *logrus.Logger) (*Client, error) {
+	if n < 1 {
+		n = 1
+	}
+
+	ins, err := newInstruments()
+	if err != nil {
+		return nil, err
+	}
+
+	svc := arrowpb.NewArrowStreamServiceClient(conn)
+	streams := make([]*stream, 0, n)
+	for i := 0; i < n; i++ {
+		c, err := svc.BatchArrowRecords(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("opening arrow stream %d/%d: %w", i+1, n, err)
+		}
+		streams = append(streams, &stream{client: c})
+	}
+
+	return &Client{streams: streams, log: log, ins: ins}, nil
+}
+
+// Send dispatches batch to the stream with the fewest outstanding acks
+// and blocks until that batch is acknowledged. Picking the target from
+// inFlight is best-effort (two concurrent Sends can race and pick the
+// same stream), so the actual Send/Recv pair is guarded by that
+// stream's mu: a losing racer simply queues behind the winner instead
+// of corrupting the stream by calling Send or Recv concurrently.
+func (c *Client) Send(batch *arrowpb.BatchArrowRecords) (*arrowpb.BatchStatus, error) {
+	target := c.streams[0]
+	for _, s := range c.streams[1:] {
+		if s.inFlight.Load() < target.inFlight.Load() {
+			target = s
+		}
+	}
+
+	target.inFlight.Add(1)
+	defer target.inFlight.Add(-1)
+
+	target.mu.Lock()
+	defer target.mu.Unlock()
+
+	if err := target.client.Send(batch); err != nil {
+		return nil, err
+	}
+	return target.client.Recv()
+}
+
+// Close shuts down every stream in the pool.
+func (c *Client) Close() error {
+	var firstErr error
+	for _, s := range c.streams {
+		if err := s.client.CloseSend(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
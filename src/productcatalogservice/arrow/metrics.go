@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package arrow
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const meterName = "github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/arrow"
+
+// instruments bundles the OTel-Arrow transport metrics shared by the
+// server and client sides of the stream.
+type instruments struct {
+	streamsActive    metric.Int64UpDownCounter
+	batchesReceived  metric.Int64Counter
+	batchSize        metric.Int64Histogram
+	dictionaryResets metric.Int64Counter
+	fallbackDials    metric.Int64Counter
+}
+
+func newInstruments() (*instruments, error) {
+	meter := otel.Meter(meterName)
+
+	streamsActive, err := meter.Int64UpDownCounter(
+		"arrow.streams.active",
+		metric.WithDescription("Number of currently open OTel-Arrow BatchArrowRecords streams"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	batchesReceived, err := meter.Int64Counter(
+		"arrow.batches",
+		metric.WithDescription("Number of Arrow record batches processed, by payload type"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize, err := meter.Int64Histogram(
+		"arrow.batch.payloads",
+		metric.WithDescription("Number of Arrow payloads carried per BatchArrowRecords message"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dictionaryResets, err := meter.Int64Counter(
+		"arrow.dictionary.resets",
+		metric.WithDescription("Number of times a sender reported a dictionary cache reset"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fallbackDials, err := meter.Int64Counter(
+		"arrow.client.fallbacks",
+		metric.WithDescription("Number of client dials that fell back to plain OTLP because the peer did not advertise Arrow support or Arrow stream setup failed"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &instruments{
+		streamsActive:    streamsActive,
+		batchesReceived:  batchesReceived,
+		batchSize:        batchSize,
+		dictionaryResets: dictionaryResets,
+		fallbackDials:    fallbackDials,
+	}, nil
+}
@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package arrow implements the server and client sides of the OTel-Arrow
+// (OTAP) transport for ProductCatalogService: a bidirectional
+// BatchArrowRecords stream carrying columnar Arrow record batches, used
+// in place of one OTLP unary call per batch when both peers support it.
+package arrow
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+
+	arrowpb "github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/genproto/oteldemo/arrow"
+)
+
+// Server implements arrowpb.ArrowStreamServiceServer on top of the
+// existing product catalog. It tracks which schema_ids it has already
+// seen, purely to log the first batch under a new schema. The
+// arrow.dictionary.resets metric is fed by the sender's
+// dictionaries_reset flag, not by schema changes.
+type Server struct {
+	arrowpb.UnimplementedArrowStreamServiceServer
+
+	log *logrus.Logger
+	ins *instruments
+
+	mu          sync.Mutex
+	seenSchemas map[string]bool
+}
+
+// NewServer builds an Arrow transport server. Call Register to attach it
+// to a *grpc.Server alongside the existing unary ProductCatalogService.
+func NewServer(log *logrus.Logger) (*Server, error) {
+	ins, err := newInstruments()
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		log:         log,
+		ins:         ins,
+		seenSchemas: make(map[string]bool),
+	}, nil
+}
+
+// BatchArrowRecords receives a stream of Arrow-encoded batches and acks
+// each one in turn.
+func (s *Server) BatchArrowRecords(stream arrowpb.ArrowStreamService_BatchArrowRecordsServer) error {
+	ctx := stream.Context()
+	s.ins.streamsActive.Add(ctx, 1)
+	defer s.ins.streamsActive.Add(ctx, -1)
+
+	for {
+		batch, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := stream.Send(s.processBatch(ctx, batch)); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) processBatch(ctx context.Context, batch *arrowpb.BatchArrowRecords) *arrowpb.BatchStatus {
+	s.ins.batchSize.Record(ctx, int64(len(batch.ArrowPayloads)))
+
+	if batch.DictionariesReset {
+		s.ins.dictionaryResets.Add(ctx, 1)
+	}
+
+	for _, payload := range batch.ArrowPayloads {
+		s.mu.Lock()
+		firstSeen := !s.seenSchemas[payload.SchemaId]
+		s.seenSchemas[payload.SchemaId] = true
+		s.mu.Unlock()
+
+		if firstSeen {
+			s.log.WithFields(logrus.Fields{
+				"schema_id": payload.SchemaId,
+				"type":      payload.Type.String(),
+			}).Info("arrow: new schema observed")
+		}
+
+		s.ins.batchesReceived.Add(ctx, 1, attribute.String("payload_type", payload.Type.String()))
+	}
+
+	return &arrowpb.BatchStatus{
+		BatchId: batch.BatchId,
+		Status: &arrowpb.StatusMessage{
+			Code: arrowpb.StatusMessage_OK,
+		},
+	}
+}
+
+// Register attaches the Arrow transport service to srv. It is only
+// called from main() when PRODUCT_CATALOG_ARROW_ENABLED is set, so the
+// plain OTLP-only deployment footprint is unchanged by default.
+func Register(srv *grpc.Server, s *Server) {
+	arrowpb.RegisterArrowStreamServiceServer(srv, s)
+}
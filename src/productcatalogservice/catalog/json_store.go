@@ -0,0 +1,219 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package catalog
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	pb "github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/genproto/oteldemo"
+	"github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/search"
+)
+
+// defaultPageSize is used when a SearchOptions.PageSize isn't set.
+const defaultPageSize = 20
+
+// snapshot bundles the product list and the search index built over it,
+// so Search never sees a product slice and an index that disagree.
+type snapshot struct {
+	products []*pb.Product
+	index    *search.Index
+}
+
+// JSONStore is the original backend: every *.json file under dir is
+// unmarshaled as a ListProductsResponse and the products are concatenated
+// in memory. A filesystem watcher on dir rebuilds the search index
+// whenever a product file changes, so operators can edit the catalog
+// without restarting the service.
+type JSONStore struct {
+	dir    string
+	k1, b  float64
+	snap   atomic.Pointer[snapshot]
+	log    *logrus.Logger
+	closed chan struct{}
+}
+
+// NewJSONStore loads every *.json file in dir into memory, builds the
+// BM25 search index over it, and starts watching dir for changes.
+func NewJSONStore(dir string) (*JSONStore, error) {
+	s := &JSONStore{
+		dir:    dir,
+		k1:     search.DefaultK1,
+		b:      search.DefaultB,
+		log:    logrus.StandardLogger(),
+		closed: make(chan struct{}),
+	}
+
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		s.log.Warnf("catalog: could not start filesystem watcher on %s, index will not auto-refresh: %v", dir, err)
+		return s, nil
+	}
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		s.log.Warnf("catalog: could not watch %s, index will not auto-refresh: %v", dir, err)
+		return s, nil
+	}
+	go s.watch(watcher)
+
+	return s, nil
+}
+
+func (s *JSONStore) watch(watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+	for {
+		select {
+		case <-s.closed:
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !strings.HasSuffix(event.Name, ".json") {
+				continue
+			}
+			if err := s.reload(); err != nil {
+				s.log.Warnf("catalog: failed to reload %s after %s: %v", s.dir, event.Op, err)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			s.log.Warnf("catalog: filesystem watch error on %s: %v", s.dir, err)
+		}
+	}
+}
+
+// Close stops the filesystem watcher. Safe to call on a store whose
+// watcher failed to start.
+func (s *JSONStore) Close() {
+	close(s.closed)
+}
+
+func (s *JSONStore) reload() error {
+	products, err := loadProductFiles(s.dir)
+	if err != nil {
+		return err
+	}
+
+	s.snap.Store(&snapshot{
+		products: products,
+		index:    search.Build(products, s.k1, s.b),
+	})
+	return nil
+}
+
+func loadProductFiles(dir string) ([]*pb.Product, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonFiles := make([]fs.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".json") {
+			info, err := entry.Info()
+			if err != nil {
+				return nil, err
+			}
+			jsonFiles = append(jsonFiles, info)
+		}
+	}
+
+	var products []*pb.Product
+	for _, f := range jsonFiles {
+		jsonData, err := os.ReadFile(dir + "/" + f.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		var res pb.ListProductsResponse
+		if err := protojson.Unmarshal(jsonData, &res); err != nil {
+			return nil, err
+		}
+
+		products = append(products, res.Products...)
+	}
+
+	return products, nil
+}
+
+func (s *JSONStore) List(ctx context.Context) ([]*pb.Product, error) {
+	return s.snap.Load().products, nil
+}
+
+func (s *JSONStore) Get(ctx context.Context, id string) (*pb.Product, error) {
+	for _, product := range s.snap.Load().products {
+		if product.Id == id {
+			return product, nil
+		}
+	}
+	return nil, &ErrNotFound{ID: id}
+}
+
+func (s *JSONStore) Search(ctx context.Context, opts SearchOptions) (SearchResult, error) {
+	pageSize := int(opts.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	queryHash := search.QueryHash(opts.Query)
+	offset := 0
+	if opts.PageToken != "" {
+		var tokenHash uint64
+		var err error
+		offset, tokenHash, err = search.DecodeToken(opts.PageToken)
+		if err != nil {
+			return SearchResult{}, &ErrInvalidPageToken{Reason: err.Error()}
+		}
+		if tokenHash != queryHash {
+			return SearchResult{}, &ErrInvalidPageToken{Reason: "page_token does not match query"}
+		}
+	}
+
+	index := s.snap.Load().index
+	all := index.Query(opts.Query, float64(opts.MinScore), opts.Categories)
+
+	total := len(all)
+	if offset > total {
+		offset = total
+	}
+	end := offset + pageSize
+	if end > total {
+		end = total
+	}
+
+	page := make([]*pb.Product, 0, end-offset)
+	for _, r := range all[offset:end] {
+		page = append(page, r.Product)
+	}
+
+	nextPageToken := ""
+	if end < total {
+		nextPageToken = search.EncodeToken(end, queryHash)
+	}
+
+	var topScore float32
+	if len(all) > 0 {
+		topScore = float32(all[0].Score)
+	}
+
+	return SearchResult{
+		Products:      page,
+		TotalMatches:  int32(total),
+		NextPageToken: nextPageToken,
+		TopScore:      topScore,
+	}, nil
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const createTableSQL = `
+CREATE TABLE IF NOT EXISTS products (
+	id                  TEXT PRIMARY KEY,
+	name                TEXT NOT NULL,
+	description         TEXT NOT NULL,
+	picture             TEXT NOT NULL,
+	price_currency_code TEXT NOT NULL,
+	price_units         BIGINT NOT NULL,
+	price_nanos         INTEGER NOT NULL,
+	categories          TEXT[] NOT NULL DEFAULT '{}'
+)`
+
+// SeedFromJSON creates the products table if it does not exist and, only
+// if the table is empty, loads it from the *.json files in jsonDir. It is
+// meant to be run once against a fresh database when
+// PRODUCT_CATALOG_BACKEND=postgres is first enabled.
+func SeedFromJSON(ctx context.Context, pool *pgxpool.Pool, jsonDir string) error {
+	if _, err := pool.Exec(ctx, createTableSQL); err != nil {
+		return fmt.Errorf("creating products table: %w", err)
+	}
+
+	var count int
+	if err := pool.QueryRow(ctx, "SELECT count(*) FROM products").Scan(&count); err != nil {
+		return fmt.Errorf("counting existing products: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	products, err := loadProductFiles(jsonDir)
+	if err != nil {
+		return fmt.Errorf("loading seed data from %s: %w", jsonDir, err)
+	}
+
+	batch := &pgx.Batch{}
+	for _, p := range products {
+		batch.Queue(
+			`INSERT INTO products (id, name, description, picture, price_currency_code, price_units, price_nanos, categories)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			 ON CONFLICT (id) DO NOTHING`,
+			p.Id, p.Name, p.Description, p.Picture,
+			p.PriceUsd.CurrencyCode, p.PriceUsd.Units, p.PriceUsd.Nanos, p.Categories,
+		)
+	}
+
+	results := pool.SendBatch(ctx, batch)
+	defer results.Close()
+	for range products {
+		if _, err := results.Exec(); err != nil {
+			return fmt.Errorf("seeding product row: %w", err)
+		}
+	}
+
+	return nil
+}
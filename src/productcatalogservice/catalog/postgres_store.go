@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+
+	pb "github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/genproto/oteldemo"
+)
+
+// PostgresStore serves the catalog out of a Postgres table, pushing
+// filtering down into SQL instead of scanning in Go. Connection details
+// come from the standard PG* environment variables (PGHOST, PGUSER,
+// PGPASSWORD, PGDATABASE, ...), which pgx's connection string parser
+// reads when given an empty DSN.
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresStore opens a pool against the database named by the PG*
+// env vars and attaches queryTracer so every query emits an OTel span.
+func NewPostgresStore(ctx context.Context) (*PostgresStore, error) {
+	cfg, err := pgxpool.ParseConfig("")
+	if err != nil {
+		return nil, fmt.Errorf("parsing postgres config from PG* env vars: %w", err)
+	}
+	cfg.ConnConfig.Tracer = newQueryTracer()
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to postgres: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("pinging postgres: %w", err)
+	}
+
+	return &PostgresStore{pool: pool}, nil
+}
+
+func (s *PostgresStore) Close() {
+	s.pool.Close()
+}
+
+// Pool exposes the underlying connection pool so the caller can run the
+// first-run seed migration against it.
+func (s *PostgresStore) Pool() *pgxpool.Pool {
+	return s.pool
+}
+
+func (s *PostgresStore) List(ctx context.Context) ([]*pb.Product, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, name, description, picture, price_currency_code, price_units, price_nanos, categories
+		FROM products`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanProducts(rows)
+}
+
+func (s *PostgresStore) Get(ctx context.Context, id string) (*pb.Product, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, name, description, picture, price_currency_code, price_units, price_nanos, categories
+		FROM products WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products, err := scanProducts(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return nil, &ErrNotFound{ID: id}
+	}
+	return products[0], nil
+}
+
+// postgresDefaultPageSize mirrors the JSON backend's defaultPageSize; the two
+// backends' page sizes don't need to match exactly, but should be in
+// the same ballpark so switching PRODUCT_CATALOG_BACKEND doesn't
+// drastically change client-visible paging behavior.
+const postgresDefaultPageSize = 20
+
+// Search matches via ILIKE rather than the JSON backend's BM25 index:
+// ranking by relevance would need a tsvector column and a migration to
+// populate it, which is out of scope here. Results are ordered by id so
+// paging is at least stable across calls. Since nothing is ranked,
+// opts.MinScore is ignored (logged, not errored, since it's a
+// reasonable value to carry over when switching PRODUCT_CATALOG_BACKEND
+// from json without also touching client code).
+func (s *PostgresStore) Search(ctx context.Context, opts SearchOptions) (SearchResult, error) {
+	pageSize := int(opts.PageSize)
+	if pageSize <= 0 {
+		pageSize = postgresDefaultPageSize
+	}
+
+	if opts.MinScore != 0 {
+		logrus.StandardLogger().Warnf("catalog: min_score=%v requested against the postgres backend, which does not rank results and ignores it", opts.MinScore)
+	}
+
+	offset := 0
+	if opts.PageToken != "" {
+		parsed, err := strconv.Atoi(opts.PageToken)
+		if err != nil {
+			return SearchResult{}, &ErrInvalidPageToken{Reason: err.Error()}
+		}
+		offset = parsed
+	}
+
+	const where = `(name ILIKE '%' || $1 || '%' OR description ILIKE '%' || $1 || '%')
+		AND ($2::text[] IS NULL OR categories && $2::text[])`
+
+	var total int
+	if err := s.pool.QueryRow(ctx, "SELECT count(*) FROM products WHERE "+where,
+		opts.Query, categoriesArg(opts.Categories)).Scan(&total); err != nil {
+		return SearchResult{}, fmt.Errorf("counting search matches: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, name, description, picture, price_currency_code, price_units, price_nanos, categories
+		FROM products
+		WHERE `+where+`
+		ORDER BY id
+		LIMIT $3 OFFSET $4`,
+		opts.Query, categoriesArg(opts.Categories), pageSize, offset)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	defer rows.Close()
+
+	products, err := scanProducts(rows)
+	if err != nil {
+		return SearchResult{}, err
+	}
+
+	nextPageToken := ""
+	if offset+len(products) < total {
+		nextPageToken = strconv.Itoa(offset + len(products))
+	}
+
+	return SearchResult{
+		Products:      products,
+		TotalMatches:  int32(total),
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// categoriesArg returns nil for an empty filter so the "IS NULL" branch
+// of the WHERE clause above disables the categories overlap check
+// entirely, rather than matching only products with zero categories.
+func categoriesArg(categories []string) []string {
+	if len(categories) == 0 {
+		return nil
+	}
+	return categories
+}
+
+func scanProducts(rows pgx.Rows) ([]*pb.Product, error) {
+	var products []*pb.Product
+	for rows.Next() {
+		var (
+			p            pb.Product
+			currencyCode string
+			units        int64
+			nanos        int32
+			categories   []string
+		)
+		p.PriceUsd = &pb.Money{}
+		if err := rows.Scan(&p.Id, &p.Name, &p.Description, &p.Picture, &currencyCode, &units, &nanos, &categories); err != nil {
+			return nil, err
+		}
+		p.PriceUsd.CurrencyCode = currencyCode
+		p.PriceUsd.Units = units
+		p.PriceUsd.Nanos = nanos
+		p.Categories = categories
+		products = append(products, &p)
+	}
+	return products, rows.Err()
+}
@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package catalog abstracts where ProductCatalogService's products come
+// from, so the JSON-file backend that ships with the demo and a
+// Postgres-backed one can sit behind the same interface.
+package catalog
+
+import (
+	"context"
+
+	pb "github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/genproto/oteldemo"
+)
+
+// Store is the product catalog backend used by the RPC handlers. All
+// three methods are read-only; the demo does not expose catalog writes.
+type Store interface {
+	List(ctx context.Context) ([]*pb.Product, error)
+	Get(ctx context.Context, id string) (*pb.Product, error)
+	// Search ranks and paginates products matching opts.Query.
+	// Implementations decide how matching and ranking work (BM25 over an
+	// in-memory inverted index, SQL ILIKE, ...); see the json and
+	// postgres backends.
+	Search(ctx context.Context, opts SearchOptions) (SearchResult, error)
+}
+
+// SearchOptions carries SearchProducts' pagination, ranking and
+// filtering knobs through to a Store implementation.
+type SearchOptions struct {
+	Query string
+	// PageSize <= 0 means "use the backend's default".
+	PageSize int32
+	// PageToken is opaque and backend-specific; empty means "first page".
+	PageToken string
+	// MinScore excludes low-relevance matches; 0 disables the floor.
+	// Only the JSON backend's BM25 index ranks results, so this is a
+	// no-op against PostgresStore: ILIKE matching has no notion of
+	// relevance to filter on.
+	MinScore float32
+	// Categories, if non-empty, restricts results to products in at
+	// least one of the listed categories.
+	Categories []string
+}
+
+// SearchResult is what a Store.Search call returns: one page of ranked
+// products plus enough bookkeeping for the caller to fetch the next one.
+type SearchResult struct {
+	Products []*pb.Product
+	// TotalMatches is the number of products matching Query across all
+	// pages, independent of PageSize.
+	TotalMatches int32
+	// NextPageToken is empty once the last page has been returned.
+	NextPageToken string
+	// TopScore is the highest-ranked result's relevance score, 0 for
+	// backends (like postgres's ILIKE) that don't rank results.
+	TopScore float32
+}
+
+// ErrNotFound is returned by Get when no product has the given id.
+type ErrNotFound struct {
+	ID string
+}
+
+func (e *ErrNotFound) Error() string {
+	return "product not found: " + e.ID
+}
+
+// ErrInvalidPageToken is returned by Search when PageToken is malformed
+// or does not match Query, so callers can tell a bad client-supplied
+// token apart from a genuine backend failure.
+type ErrInvalidPageToken struct {
+	Reason string
+}
+
+func (e *ErrInvalidPageToken) Error() string {
+	return "invalid page_token: " + e.Reason
+}
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package faultinjector evaluates OpenFeature flags to decide whether an
+// RPC should be delayed, failed, crashed or truncated, replacing the
+// hardcoded failRatePerThousand/panic path that used to live directly in
+// GetProduct.
+package faultinjector
+
+// Mode is one of the fault behaviors a pcs.fault.<Method> flag can select.
+type Mode string
+
+const (
+	ModeLatency         Mode = "latency"
+	ModeError           Mode = "error"
+	ModePanic           Mode = "panic"
+	ModePartialResponse Mode = "partial_response"
+)
+
+// Fault is the decoded value of a pcs.fault.<Method> flag, e.g.
+// {"mode": "latency", "probability": 10, "params": {"max_ms": 250}}.
+type Fault struct {
+	Mode        Mode
+	Probability float64 // percent, 0-100
+	Params      map[string]any
+}
+
+func parseFault(data map[string]any) *Fault {
+	mode, _ := data["mode"].(string)
+	if mode == "" {
+		return nil
+	}
+
+	probability, _ := data["probability"].(float64)
+	params, _ := data["params"].(map[string]any)
+
+	return &Fault{
+		Mode:        Mode(mode),
+		Probability: probability,
+		Params:      params,
+	}
+}
+
+func (f *Fault) intParam(key string, fallback int) int {
+	if f.Params == nil {
+		return fallback
+	}
+	v, ok := f.Params[key].(float64) // JSON numbers decode as float64
+	if !ok {
+		return fallback
+	}
+	return int(v)
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package faultinjector
+
+import (
+	"context"
+	"math/rand/v2"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Injector decides, per RPC method, whether a fault should be injected
+// this call. Flags are keyed "pcs.fault.<Method>" (e.g.
+// "pcs.fault.GetProduct") and hold a structured {mode, probability,
+// params} object.
+type Injector struct {
+	client *openfeature.Client
+}
+
+// New builds an Injector over the given OpenFeature client. flagd must
+// already be set as the global provider (see main's openfeature.SetProvider
+// call) before evaluations will return anything but the zero value.
+func New(client *openfeature.Client) *Injector {
+	return &Injector{client: client}
+}
+
+// Evaluate returns the fault to inject for method this call, or nil if
+// none should fire - either because the flag is unset, malformed, or its
+// probability roll didn't hit.
+func (i *Injector) Evaluate(ctx context.Context, method string) *Fault {
+	value, err := i.client.ObjectValue(ctx, "pcs.fault."+method, map[string]any{}, openfeature.EvaluationContext{})
+	if err != nil {
+		return nil
+	}
+
+	data, ok := value.(map[string]any)
+	if !ok || len(data) == 0 {
+		return nil
+	}
+
+	fault := parseFault(data)
+	if fault == nil {
+		return nil
+	}
+
+	if !roll(fault.Probability) {
+		return nil
+	}
+	return fault
+}
+
+// roll reports whether a probability-percent chance hit. Using
+// math/rand/v2's package-level functions means there's no per-call
+// rand.Seed(time.Now().UnixNano()) to forget or get wrong - the
+// generator is auto-seeded once, globally.
+func roll(probabilityPercent float64) bool {
+	if probabilityPercent <= 0 {
+		return false
+	}
+	return rand.Float64()*100 < probabilityPercent
+}
@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package faultinjector
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/genproto/oteldemo"
+)
+
+// UnaryServerInterceptor consults inj before every unary RPC. When a
+// fault fires it emits a "fault.injected" span event and a
+// fault_injections_total metric, then applies the fault's mode.
+func UnaryServerInterceptor(inj *Injector) (grpc.UnaryServerInterceptor, error) {
+	ins, err := newInstruments()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method := methodName(info.FullMethod)
+
+		fault := inj.Evaluate(ctx, method)
+		if fault == nil {
+			return handler(ctx, req)
+		}
+
+		span := trace.SpanFromContext(ctx)
+		span.AddEvent("fault.injected", trace.WithAttributes(
+			attribute.String("fault.method", method),
+			attribute.String("fault.mode", string(fault.Mode)),
+		))
+		ins.injectionsTotal.Add(ctx, 1,
+			attribute.String("method", method),
+			attribute.String("mode", string(fault.Mode)),
+		)
+
+		switch fault.Mode {
+		case ModeLatency:
+			time.Sleep(jitter(fault))
+			return handler(ctx, req)
+
+		case ModeError:
+			return nil, status.Error(errorCode(fault), fmt.Sprintf("fault injected for %s", method))
+
+		case ModePanic:
+			panic(fmt.Sprintf("fault injected panic for %s", method))
+
+		case ModePartialResponse:
+			resp, err := handler(ctx, req)
+			if err != nil {
+				return resp, err
+			}
+			return truncate(resp, fault.intParam("n", 1)), nil
+
+		default:
+			return handler(ctx, req)
+		}
+	}, nil
+}
+
+func methodName(fullMethod string) string {
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[i+1:]
+	}
+	return fullMethod
+}
+
+// jitter draws a uniform delay in [min_ms, max_ms], defaulting to a
+// modest 10-100ms band when unset.
+func jitter(f *Fault) time.Duration {
+	minMs := f.intParam("min_ms", 10)
+	maxMs := f.intParam("max_ms", 100)
+	if maxMs < minMs {
+		maxMs = minMs
+	}
+	return time.Duration(minMs+rand.IntN(maxMs-minMs+1)) * time.Millisecond
+}
+
+func errorCode(f *Fault) codes.Code {
+	code := f.intParam("code", int(codes.Internal))
+	return codes.Code(code)
+}
+
+func truncate(resp any, n int) any {
+	list, ok := resp.(*pb.ListProductsResponse)
+	if !ok || n <= 0 || len(list.Products) <= n {
+		return resp
+	}
+	return &pb.ListProductsResponse{Products: list.Products[:n]}
+}
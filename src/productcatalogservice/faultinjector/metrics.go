@@ -0,0 +1,29 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package faultinjector
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const meterName = "github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/faultinjector"
+
+type instruments struct {
+	injectionsTotal metric.Int64Counter
+}
+
+func newInstruments() (*instruments, error) {
+	meter := otel.Meter(meterName)
+
+	injectionsTotal, err := meter.Int64Counter(
+		"fault_injections_total",
+		metric.WithDescription("Number of RPCs whose response was altered by the fault injector, by method and mode"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &instruments{injectionsTotal: injectionsTotal}, nil
+}
@@ -5,21 +5,19 @@ package main
 //go:generate go install google.golang.org/protobuf/cmd/protoc-gen-go
 //go:generate go install google.golang.org/grpc/cmd/protoc-gen-go-grpc
 //go:generate protoc --go_out=./ --go-grpc_out=./ --proto_path=../../pb ../../pb/demo.proto
+//go:generate protoc --go_out=./ --go-grpc_out=./ --proto_path=../../pb ../../pb/arrow.proto
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"io/fs"
 	"net"
 	"os"
 	"os/signal"
-	"strings"
 	"sync"
 	"syscall"
 	"time"
-	
-	"errors"
-	"math/rand"
+
 	"strconv"
 
 
@@ -41,22 +39,26 @@ import (
 	otelhooks "github.com/open-feature/go-sdk-contrib/hooks/open-telemetry/pkg"
 	flagd "github.com/open-feature/go-sdk-contrib/providers/flagd/pkg"
 	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/arrow"
+	"github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/catalog"
+	"github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/faultinjector"
 	pb "github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/genproto/oteldemo"
+	arrowpb "github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/genproto/oteldemo/arrow"
+	"github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/queue"
+	"github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/sentryreport"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 var (
 	log               *logrus.Logger
-	catalog           []*pb.Product
 	resource          *sdkresource.Resource
 	initResourcesOnce sync.Once
-	failRatePerThousand	  int
 )
 
 func init() {
@@ -70,27 +72,6 @@ func init() {
                 },
                 TimestampFormat: time.RFC3339Nano,
         }
-
-	// Get the failure rate per 1000 calls
-	failRatePerThousand=5
-	envValue := os.Getenv("PRODUCT_CATALOG_FAILS_PER_THOUSAND_CALLS")
-	if envValue != "" {
-	    intValue, err := strconv.Atoi(envValue)
-	    if err != nil {
-		fmt.Printf("Error converting string to integer: %s\n", err)
-	    } else {
-		failRatePerThousand = intValue
-	    }
-        }
-
-	log.Infof("This service will fail on calls to getProducts approx %d out of every thousand calls", failRatePerThousand)
-
-	var err error
-	catalog, err = readProductFiles()
-	if err != nil {
-		log.Fatalf("Reading Product Files: %v", err)
-		os.Exit(1)
-	}
 }
 
 func initResource() *sdkresource.Resource {
@@ -160,12 +141,42 @@ func main() {
 	}()
 	openfeature.SetProvider(flagd.NewProvider())
 
-	err := runtime.Start(runtime.WithMinimumReadMemStatsInterval(time.Second))
+	sentryReporter, err := sentryreport.Init(sentryreport.ConfigFromEnv(), log)
+	if err != nil {
+		log.Fatalf("Sentry Setup: %v", err)
+	}
+	log.AddHook(sentryreport.NewLogrusHook())
+
+	err = runtime.Start(runtime.WithMinimumReadMemStatsInterval(time.Second))
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	svc := &productCatalog{}
+	store, err := setupCatalogStore(context.Background())
+	if err != nil {
+		log.Fatalf("Catalog Store Setup: %v", err)
+	}
+
+	reqQueue, queueStorage, err := setupQueue(log)
+	if err != nil {
+		log.Fatalf("Queue Setup: %v", err)
+	}
+
+	svc := &productCatalog{reqQueue: reqQueue, store: store}
+
+	if reqQueue != nil {
+		reqQueue.RegisterHandler("GetProduct", func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return svc.getProduct(ctx, req.(*pb.GetProductRequest))
+		})
+		reqQueue.RegisterHandler("SearchProducts", func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return svc.searchProducts(ctx, req.(*pb.SearchProductsRequest))
+		})
+		if err := reqQueue.Start(context.Background()); err != nil {
+			log.Fatalf("Queue Start: %v", err)
+		}
+		log.Info("persistent request queue enabled in front of GetProduct/SearchProducts")
+	}
+
 	var port string
 	mustMapEnv(&port, "PRODUCT_CATALOG_SERVICE_PORT")
 
@@ -176,8 +187,14 @@ func main() {
 		log.Fatalf("TCP Listen: %v", err)
 	}
 
+	faultInterceptor, err := faultinjector.UnaryServerInterceptor(faultinjector.New(openfeature.NewClient("productCatalog")))
+	if err != nil {
+		log.Fatalf("Fault Injector Setup: %v", err)
+	}
+
 	srv := grpc.NewServer(
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(sentryReporter.UnaryServerInterceptor(), faultInterceptor),
 	)
 
 	reflection.Register(srv)
@@ -185,6 +202,15 @@ func main() {
 	pb.RegisterProductCatalogServiceServer(srv, svc)
 	healthpb.RegisterHealthServer(srv, svc)
 
+	if arrowEnabled() {
+		arrowSrv, err := arrow.NewServer(log)
+		if err != nil {
+			log.Fatalf("Arrow Server Creation: %v", err)
+		}
+		arrow.Register(srv, arrowSrv)
+		log.Info("OTel-Arrow (OTAP) transport enabled alongside OTLP")
+	}
+
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGKILL)
 	defer cancel()
 
@@ -194,55 +220,128 @@ func main() {
 		}
 	}()
 
+	if arrowEnabled() {
+		go selfCheckArrowTransport(ctx, fmt.Sprintf("localhost:%s", port))
+	}
+
 	<-ctx.Done()
 
 	srv.GracefulStop()
+
+	if !sentryReporter.Flush() {
+		log.Warn("sentry: timed out flushing queued events at shutdown")
+	}
+
+	if reqQueue != nil {
+		flushCtx, flushCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := reqQueue.Shutdown(flushCtx); err != nil {
+			log.Warnf("queue: in-flight requests left on disk at shutdown: %v", err)
+		}
+		flushCancel()
+		if err := queueStorage.Close(); err != nil {
+			log.Warnf("queue: failed to close storage: %v", err)
+		}
+	}
+
+	if closer, ok := store.(interface{ Close() }); ok {
+		closer.Close()
+	}
+
 	log.Println("ProductCatalogService gRPC server stopped")
 }
 
 type productCatalog struct {
 	pb.UnimplementedProductCatalogServiceServer
+	reqQueue *queue.Queue
+	store    catalog.Store
 }
 
-func readProductFiles() ([]*pb.Product, error) {
+// productsDir is where the JSON backend's source files, and the
+// Postgres backend's first-run seed data, both live.
+const productsDir = "./products"
 
-	// find all .json files in the products directory
-	entries, err := os.ReadDir("./products")
-	if err != nil {
-		return nil, err
+// setupCatalogStore picks the catalog backend named by
+// PRODUCT_CATALOG_BACKEND ("json", the default, or "postgres").
+func setupCatalogStore(ctx context.Context) (catalog.Store, error) {
+	backend := os.Getenv("PRODUCT_CATALOG_BACKEND")
+	if backend == "" {
+		backend = "json"
 	}
 
-	jsonFiles := make([]fs.FileInfo, 0, len(entries))
-	for _, entry := range entries {
-		if strings.HasSuffix(entry.Name(), ".json") {
-			info, err := entry.Info()
-			if err != nil {
-				return nil, err
-			}
-			jsonFiles = append(jsonFiles, info)
+	switch backend {
+	case "json":
+		store, err := catalog.NewJSONStore(productsDir)
+		if err != nil {
+			return nil, fmt.Errorf("loading %s: %w", productsDir, err)
 		}
-	}
+		return store, nil
 
-	// read the contents of each .json file and unmarshal into a ListProductsResponse
-	// then append the products to the catalog
-	var products []*pb.Product
-	for _, f := range jsonFiles {
-		jsonData, err := os.ReadFile("./products/" + f.Name())
+	case "postgres":
+		store, err := catalog.NewPostgresStore(ctx)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("connecting to postgres: %w", err)
 		}
-
-		var res pb.ListProductsResponse
-		if err := protojson.Unmarshal(jsonData, &res); err != nil {
-			return nil, err
+		if err := catalog.SeedFromJSON(ctx, store.Pool(), productsDir); err != nil {
+			store.Close()
+			return nil, fmt.Errorf("seeding postgres from %s: %w", productsDir, err)
 		}
+		return store, nil
 
-		products = append(products, res.Products...)
+	default:
+		return nil, fmt.Errorf("unknown PRODUCT_CATALOG_BACKEND %q (want json or postgres)", backend)
+	}
+}
+
+// arrowEnabled reports whether OTel-Arrow (OTAP) transport support
+// should be registered alongside the standard OTLP gRPC service. Off by
+// default so existing deployments are unaffected.
+func arrowEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("PRODUCT_CATALOG_ARROW_ENABLED"))
+	return enabled
+}
+
+// setupQueue builds the persistent request queue when
+// PRODUCT_CATALOG_QUEUE_DIR is set, or returns a nil queue (and storage)
+// when it is not, leaving GetProduct/SearchProducts to run inline as
+// before.
+func setupQueue(log *logrus.Logger) (*queue.Queue, queue.Storage, error) {
+	dir := os.Getenv("PRODUCT_CATALOG_QUEUE_DIR")
+	if dir == "" {
+		return nil, nil, nil
+	}
+
+	storage, err := queue.NewBoltStorage(dir)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	log.Infof("Loaded %d products", len(products))
+	cfg := queue.Config{
+		QueueSize:    envInt("PRODUCT_CATALOG_QUEUE_SIZE", 1000),
+		NumConsumers: queueNumConsumers(),
+	}
+	q, err := queue.New(storage, cfg, log)
+	if err != nil {
+		storage.Close()
+		return nil, nil, err
+	}
+	return q, storage, nil
+}
+
+func queueNumConsumers() int {
+	return envInt("PRODUCT_CATALOG_QUEUE_NUM_CONSUMERS", 4)
+}
 
-	return products, nil
+func envInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		log.Warnf("Environment Variable %q is not an integer, using default %d: %v", key, fallback, err)
+		return fallback
+	}
+	return intValue
 }
 
 func mustMapEnv(target *string, key string) {
@@ -264,13 +363,39 @@ func (p *productCatalog) Watch(req *healthpb.HealthCheckRequest, ws healthpb.Hea
 func (p *productCatalog) ListProducts(ctx context.Context, req *pb.Empty) (*pb.ListProductsResponse, error) {
 	span := trace.SpanFromContext(ctx)
 
+	products, err := p.store.List(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "listing products: %v", err)
+	}
+
 	span.SetAttributes(
-		attribute.Int("app.products.count", len(catalog)),
+		attribute.Int("app.products.count", len(products)),
 	)
-	return &pb.ListProductsResponse{Products: catalog}, nil
+	return &pb.ListProductsResponse{Products: products}, nil
 }
 
 func (p *productCatalog) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.Product, error) {
+	if p.reqQueue == nil {
+		return p.getProduct(ctx, req)
+	}
+
+	resultCh, err := p.reqQueue.Submit(ctx, "GetProduct", req)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "queueing GetProduct request: %v", err)
+	}
+
+	select {
+	case result := <-resultCh:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Resp.(*pb.Product), nil
+	case <-ctx.Done():
+		return nil, status.Errorf(codes.DeadlineExceeded, "GetProduct request still queued: %v", ctx.Err())
+	}
+}
+
+func (p *productCatalog) getProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.Product, error) {
 	span := trace.SpanFromContext(ctx)
 	span.SetAttributes(
 		attribute.String("app.product.id", req.Id),
@@ -278,19 +403,6 @@ func (p *productCatalog) GetProduct(ctx context.Context, req *pb.GetProductReque
 	spanLogger := log.WithFields(logrus.Fields{"trace_id" :span.SpanContext().TraceID(),
 						   "span_id" : span.SpanContext().SpanID()})
 
-	// ABP: Throw an error some portion of the time
-	rand.Seed(time.Now().UnixNano()) // Seed the random number generator
-	randomNumber := rand.Intn(1000)
-	if randomNumber < failRatePerThousand {
-	    msg := fmt.Sprintf("Random fail to simulate container error. Fail rate is %d per thousand calls", failRatePerThousand)
-	    err := errors.New(msg)
-	    msg = fmt.Sprintf("Error: %s", msg)
-	    spanLogger.Errorf(msg)
-	    log.Errorf(msg)
-	    panic(err)
-	}
-
-
 	// GetProduct will fail on a specific product when feature flag is enabled
 	if p.checkProductFailure(ctx, req.Id) {
 		msg := fmt.Sprintf("Error: ProductCatalogService Fail Feature Flag Enabled")
@@ -300,19 +412,19 @@ func (p *productCatalog) GetProduct(ctx context.Context, req *pb.GetProductReque
 		return nil, status.Errorf(codes.Internal, msg)
 	}
 
-	var found *pb.Product
-	for _, product := range catalog {
-		if req.Id == product.Id {
-			found = product
-			break
-		}
-	}
-
-	if found == nil {
+	found, err := p.store.Get(ctx, req.Id)
+	if err != nil {
+		var notFound *catalog.ErrNotFound
 		msg := fmt.Sprintf("Product Not Found: %s", req.Id)
+		if !errors.As(err, &notFound) {
+			msg = fmt.Sprintf("Loading Product %s: %v", req.Id, err)
+		}
 		span.SetStatus(otelcodes.Error, msg)
 		span.AddEvent(msg)
-		return nil, status.Errorf(codes.NotFound, msg)
+		if errors.As(err, &notFound) {
+			return nil, status.Errorf(codes.NotFound, msg)
+		}
+		return nil, status.Errorf(codes.Internal, msg)
 	}
 
 	msg := fmt.Sprintf("Product Found - ID: %s, Name: %s", req.Id, found.Name)
@@ -325,19 +437,54 @@ func (p *productCatalog) GetProduct(ctx context.Context, req *pb.GetProductReque
 }
 
 func (p *productCatalog) SearchProducts(ctx context.Context, req *pb.SearchProductsRequest) (*pb.SearchProductsResponse, error) {
+	if p.reqQueue == nil {
+		return p.searchProducts(ctx, req)
+	}
+
+	resultCh, err := p.reqQueue.Submit(ctx, "SearchProducts", req)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "queueing SearchProducts request: %v", err)
+	}
+
+	select {
+	case result := <-resultCh:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Resp.(*pb.SearchProductsResponse), nil
+	case <-ctx.Done():
+		return nil, status.Errorf(codes.DeadlineExceeded, "SearchProducts request still queued: %v", ctx.Err())
+	}
+}
+
+func (p *productCatalog) searchProducts(ctx context.Context, req *pb.SearchProductsRequest) (*pb.SearchProductsResponse, error) {
 	span := trace.SpanFromContext(ctx)
 
-	var result []*pb.Product
-	for _, product := range catalog {
-		if strings.Contains(strings.ToLower(product.Name), strings.ToLower(req.Query)) ||
-			strings.Contains(strings.ToLower(product.Description), strings.ToLower(req.Query)) {
-			result = append(result, product)
+	result, err := p.store.Search(ctx, catalog.SearchOptions{
+		Query:      req.Query,
+		PageSize:   req.PageSize,
+		PageToken:  req.PageToken,
+		MinScore:   req.MinScore,
+		Categories: req.Categories,
+	})
+	if err != nil {
+		var badToken *catalog.ErrInvalidPageToken
+		if errors.As(err, &badToken) {
+			return nil, status.Errorf(codes.InvalidArgument, "searching products: %v", err)
 		}
+		return nil, status.Errorf(codes.Internal, "searching products: %v", err)
 	}
+
 	span.SetAttributes(
-		attribute.Int("app.products_search.count", len(result)),
+		attribute.Int("app.products_search.count", len(result.Products)),
+		attribute.Int("app.products_search.returned", len(result.Products)),
+		attribute.Float64("app.products_search.top_score", float64(result.TopScore)),
 	)
-	return &pb.SearchProductsResponse{Results: result}, nil
+	return &pb.SearchProductsResponse{
+		Results:       result.Products,
+		NextPageToken: result.NextPageToken,
+		TotalMatches:  result.TotalMatches,
+	}, nil
 }
 
 func (p *productCatalog) checkProductFailure(ctx context.Context, id string) bool {
@@ -358,3 +505,66 @@ func createClient(ctx context.Context, svcAddr string) (*grpc.ClientConn, error)
 		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 	)
 }
+
+// arrowStreamsPerPeer is the width of the best-of-N stream prioritizer
+// used when the Arrow transport is enabled.
+const arrowStreamsPerPeer = 3
+
+// createArrowClient dials svcAddr like createClient, then, if the Arrow
+// transport is enabled, probes the peer via gRPC reflection and opens a
+// best-of-N BatchArrowRecords stream pool against it. When the peer does
+// not advertise Arrow support the returned *arrow.Client is nil and
+// callers should fall back to plain OTLP unary calls over conn.
+func createArrowClient(ctx context.Context, svcAddr string) (conn *grpc.ClientConn, arrowClient *arrow.Client, err error) {
+	conn, err = createClient(ctx, svcAddr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !arrowEnabled() {
+		return conn, nil, nil
+	}
+	if !arrow.Supported(ctx, conn) {
+		arrow.RecordFallback(ctx)
+		return conn, nil, nil
+	}
+
+	arrowClient, err = arrow.Dial(ctx, conn, arrowStreamsPerPeer, log)
+	if err != nil {
+		arrow.RecordFallback(ctx)
+		log.Warnf("Arrow transport advertised by %s but stream setup failed, falling back to OTLP: %v", svcAddr, err)
+		return conn, nil, nil
+	}
+	return conn, arrowClient, nil
+}
+
+// selfCheckArrowTransport dials the service's own listener through
+// createArrowClient and pushes one heartbeat batch, so a transport
+// that's enabled but broken (reflection off, misconfigured peer,
+// stream setup failure) is logged right at startup instead of only
+// surfacing the first time a real peer tries to use it.
+func selfCheckArrowTransport(ctx context.Context, addr string) {
+	conn, arrowClient, err := createArrowClient(ctx, addr)
+	if err != nil {
+		log.Warnf("arrow: self-check dial to %s failed: %v", addr, err)
+		return
+	}
+	defer conn.Close()
+
+	if arrowClient == nil {
+		log.Warn("arrow: self-check did not find the Arrow transport advertised over reflection; requests will fall back to OTLP")
+		return
+	}
+	defer arrowClient.Close()
+
+	batchStatus, err := arrowClient.Send(&arrowpb.BatchArrowRecords{})
+	if err != nil {
+		log.Warnf("arrow: self-check heartbeat batch failed: %v", err)
+		return
+	}
+	if code := batchStatus.GetStatus().GetCode(); code != arrowpb.StatusMessage_OK {
+		log.Warnf("arrow: self-check heartbeat batch returned status %v", code)
+		return
+	}
+	log.Info("arrow: self-check heartbeat batch acknowledged, transport healthy")
+}
@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const meterName = "github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/queue"
+
+type instruments struct {
+	depth          metric.Int64UpDownCounter
+	enqueueLatency metric.Float64Histogram
+	dequeueLatency metric.Float64Histogram
+	dropped        metric.Int64Counter
+}
+
+func newInstruments() (*instruments, error) {
+	meter := otel.Meter(meterName)
+
+	depth, err := meter.Int64UpDownCounter(
+		"queue.depth",
+		metric.WithDescription("Number of requests currently persisted in the queue, awaiting dispatch"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	enqueueLatency, err := meter.Float64Histogram(
+		"queue.enqueue.latency",
+		metric.WithDescription("Time to durably persist a request before admitting it to the queue"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dequeueLatency, err := meter.Float64Histogram(
+		"queue.dequeue.latency",
+		metric.WithDescription("Time from a consumer picking up a request to it being acked and removed from storage"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dropped, err := meter.Int64Counter(
+		"queue.dropped",
+		metric.WithDescription("Number of requests that could not be persisted and were dropped rather than queued"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &instruments{
+		depth:          depth,
+		enqueueLatency: enqueueLatency,
+		dequeueLatency: dequeueLatency,
+		dropped:        dropped,
+	}, nil
+}
@@ -0,0 +1,315 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package queue puts a durable, resumable queue in front of
+// ProductCatalogService's read RPCs so that a restart, or a burst of
+// load past what the configured number of consumers can keep up with,
+// delays requests instead of losing them outright.
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Handler executes the decoded request for one method and returns the
+// response that would otherwise have been returned directly.
+type Handler func(ctx context.Context, req proto.Message) (proto.Message, error)
+
+// Result is delivered on the channel returned by Submit once the
+// request has been dispatched to its Handler.
+type Result struct {
+	Resp proto.Message
+	Err  error
+}
+
+// Config controls queue sizing; see the PRODUCT_CATALOG_QUEUE_* env vars
+// wired up in main.go.
+type Config struct {
+	QueueSize    int
+	NumConsumers int
+}
+
+// Queue is a bounded, disk-backed FIFO of pending RPC requests. Requests
+// are persisted to Storage before Submit returns, dispatched to their
+// Handler by a fixed-size worker pool, and deleted from Storage once the
+// Handler returns. Anything left in Storage when the process starts is
+// re-submitted before new traffic is accepted.
+type Queue struct {
+	storage      Storage
+	handlers     map[string]Handler
+	log          *logrus.Logger
+	ins          *instruments
+	numConsumers int
+
+	notify  chan uint64
+	stopCh  chan struct{}
+	nextSeq atomic.Uint64
+
+	mu      sync.Mutex
+	waiters map[uint64]chan Result
+	closed  bool
+
+	wg sync.WaitGroup
+}
+
+// New creates a queue over storage. RegisterHandler must be called for
+// every method name Submit will be asked to enqueue before Start runs.
+func New(storage Storage, cfg Config, log *logrus.Logger) (*Queue, error) {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1000
+	}
+	if cfg.NumConsumers <= 0 {
+		cfg.NumConsumers = 4
+	}
+
+	ins, err := newInstruments()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Queue{
+		storage:      storage,
+		handlers:     make(map[string]Handler),
+		log:          log,
+		ins:          ins,
+		numConsumers: cfg.NumConsumers,
+		notify:       make(chan uint64, cfg.QueueSize),
+		stopCh:       make(chan struct{}),
+		waiters:      make(map[uint64]chan Result),
+	}, nil
+}
+
+// RegisterHandler associates method (e.g. "GetProduct") with the
+// function that should run once a queued request for it is dispatched.
+func (q *Queue) RegisterHandler(method string, h Handler) {
+	q.handlers[method] = h
+}
+
+// Start launches the consumer pool sized by Config.NumConsumers, then
+// replays any envelopes left over from a previous run. Call it once,
+// after every handler has been registered. The consumers are started
+// first so a replay backlog larger than notify's buffer
+// (Config.QueueSize) drains as it is scanned instead of deadlocking
+// Start on a full channel.
+func (q *Queue) Start(ctx context.Context) error {
+	for i := 0; i < q.numConsumers; i++ {
+		q.wg.Add(1)
+		go q.consume(ctx)
+	}
+
+	var replayed int
+	if err := q.storage.Scan(ctx, func(seq uint64, _ []byte) error {
+		replayed++
+		q.ins.depth.Add(ctx, 1)
+		if seq >= q.nextSeq.Load() {
+			q.nextSeq.Store(seq + 1)
+		}
+		q.notify <- seq
+		return nil
+	}); err != nil {
+		return fmt.Errorf("replaying queue storage: %w", err)
+	}
+	if replayed > 0 {
+		q.log.Infof("queue: replayed %d un-acked request(s) from storage", replayed)
+	}
+
+	return nil
+}
+
+// Submit persists req under method and returns a channel that receives
+// exactly one Result once a consumer has dispatched it. The channel has
+// a one-slot buffer, so a consumer never blocks delivering to a caller
+// that stopped listening. Dispatch survives a crash-restart: a restarted
+// process re-dispatches the request to its Handler (though, since the
+// original caller is gone by then, the result is dropped, and only a
+// failed result is logged).
+func (q *Queue) Submit(ctx context.Context, method string, req proto.Message) (<-chan Result, error) {
+	any, err := anypb.New(req)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s request: %w", method, err)
+	}
+	any.TypeUrl = method + "/" + any.TypeUrl
+
+	data, err := proto.Marshal(any)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling %s envelope: %w", method, err)
+	}
+
+	seq := q.nextSeq.Add(1) - 1
+
+	start := time.Now()
+	if err := q.storage.Put(ctx, seq, data); err != nil {
+		q.ins.dropped.Add(ctx, 1)
+		return nil, fmt.Errorf("persisting queued request: %w", err)
+	}
+	q.ins.enqueueLatency.Record(ctx, time.Since(start).Seconds())
+	q.ins.depth.Add(ctx, 1)
+
+	resultCh := make(chan Result, 1)
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		close(resultCh)
+		return resultCh, fmt.Errorf("queue is shutting down")
+	}
+	q.waiters[seq] = resultCh
+	q.mu.Unlock()
+
+	// notify is handed off outside q.mu: it can block for as long as
+	// the buffer (Config.QueueSize) stays full, and holding the lock
+	// across that would both serialize every other Submit behind this
+	// one and stop Shutdown from ever acquiring it. stopCh lets a
+	// concurrent Shutdown unblock us instead of closing notify itself,
+	// which would otherwise race a send here. Either way out, the
+	// envelope stays durably on disk for the next Start to replay.
+	select {
+	case q.notify <- seq:
+		return resultCh, nil
+	case <-q.stopCh:
+		q.mu.Lock()
+		delete(q.waiters, seq)
+		q.mu.Unlock()
+		close(resultCh)
+		return resultCh, fmt.Errorf("queue is shutting down")
+	case <-ctx.Done():
+		q.mu.Lock()
+		delete(q.waiters, seq)
+		q.mu.Unlock()
+		close(resultCh)
+		return resultCh, ctx.Err()
+	}
+}
+
+func (q *Queue) consume(ctx context.Context) {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case seq := <-q.notify:
+			q.dispatch(ctx, seq)
+		case <-q.stopCh:
+			// Finish whatever is already buffered before exiting; Submit
+			// itself refuses anything new once stopCh is closed, and
+			// anything it loses the race to drain here is still safely
+			// on disk for the next Start to replay.
+			for {
+				select {
+				case seq := <-q.notify:
+					q.dispatch(ctx, seq)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (q *Queue) dispatch(ctx context.Context, seq uint64) {
+	start := time.Now()
+
+	data, found, err := q.storage.Get(ctx, seq)
+	if err != nil {
+		q.log.Warnf("queue: failed to load envelope %d: %v", seq, err)
+		return
+	}
+	if !found {
+		// Already acked by a previous dispatch of the same seq.
+		return
+	}
+
+	var any anypb.Any
+	result := Result{}
+	if err := proto.Unmarshal(data, &any); err != nil {
+		result.Err = fmt.Errorf("decoding queued envelope: %w", err)
+	} else {
+		method, req, err := decodeEnvelope(&any)
+		if err != nil {
+			result.Err = err
+		} else if h, ok := q.handlers[method]; ok {
+			result.Resp, result.Err = callHandler(h, ctx, req)
+		} else {
+			result.Err = fmt.Errorf("no handler registered for queued method %q", method)
+		}
+	}
+
+	if err := q.storage.Delete(ctx, seq); err != nil {
+		q.log.Warnf("queue: failed to delete acked envelope %d: %v", seq, err)
+	}
+	q.ins.depth.Add(ctx, -1)
+	q.ins.dequeueLatency.Record(ctx, time.Since(start).Seconds())
+
+	q.mu.Lock()
+	resultCh, ok := q.waiters[seq]
+	delete(q.waiters, seq)
+	q.mu.Unlock()
+
+	if !ok {
+		// Replayed from a previous process instance; no one is waiting.
+		if result.Err != nil {
+			q.log.Warnf("queue: replayed request %d failed: %v", seq, result.Err)
+		}
+		return
+	}
+	resultCh <- result
+	close(resultCh)
+}
+
+// callHandler runs h and converts a panic into a Result error instead of
+// letting it take down the process. Without this, a poison envelope
+// that panics its handler would crash on every replay after restart,
+// since the panic happens before storage.Delete ever runs.
+func callHandler(h Handler, ctx context.Context, req proto.Message) (resp proto.Message, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return h(ctx, req)
+}
+
+func decodeEnvelope(any *anypb.Any) (method string, req proto.Message, err error) {
+	for i := 0; i < len(any.TypeUrl); i++ {
+		if any.TypeUrl[i] == '/' {
+			method, typeURL := any.TypeUrl[:i], any.TypeUrl[i+1:]
+			inner := &anypb.Any{TypeUrl: typeURL, Value: any.Value}
+			msg, err := inner.UnmarshalNew()
+			return method, msg, err
+		}
+	}
+	return "", nil, fmt.Errorf("malformed queue envelope type URL %q", any.TypeUrl)
+}
+
+// Shutdown stops accepting new Submit calls and waits for every envelope
+// already persisted to either finish dispatching or remain safely on
+// disk for the next Start to replay. It does not wait longer than ctx
+// allows. notify itself is never closed, so a concurrent Submit can
+// never race a close while handing seq off to it; closing stopCh is
+// what unblocks both Submit and the consumers.
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+
+	close(q.stopCh)
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// memStorage is an in-memory Storage for tests; it has no durability
+// guarantees of its own, but the queue only needs the interface.
+type memStorage struct {
+	mu   sync.Mutex
+	data map[uint64][]byte
+}
+
+func newMemStorage() *memStorage {
+	return &memStorage{data: make(map[uint64][]byte)}
+}
+
+func (s *memStorage) Put(_ context.Context, seq uint64, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[seq] = append([]byte(nil), data...)
+	return nil
+}
+
+func (s *memStorage) Get(_ context.Context, seq uint64) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.data[seq]
+	return data, ok, nil
+}
+
+func (s *memStorage) Delete(_ context.Context, seq uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, seq)
+	return nil
+}
+
+func (s *memStorage) Scan(_ context.Context, fn func(seq uint64, data []byte) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seqs := make([]uint64, 0, len(s.data))
+	for seq := range s.data {
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	for _, seq := range seqs {
+		if err := fn(seq, s.data[seq]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memStorage) Close() error { return nil }
+
+func testLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return log
+}
+
+// TestSubmitDispatchesToRegisteredHandler is a basic round trip: Submit
+// should deliver the handler's response on the returned channel.
+func TestSubmitDispatchesToRegisteredHandler(t *testing.T) {
+	q, err := New(newMemStorage(), Config{QueueSize: 4, NumConsumers: 1}, testLogger())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	q.RegisterHandler("Echo", func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		return req, nil
+	})
+	if err := q.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer q.Shutdown(context.Background())
+
+	resultCh, err := q.Submit(context.Background(), "Echo", &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	select {
+	case result := <-resultCh:
+		if result.Err != nil {
+			t.Errorf("result.Err = %v, want nil", result.Err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Submit's result channel never received a Result")
+	}
+}
+
+// TestShutdownDoesNotBlockOnFullQueue reproduces the deadlock where a
+// Submit blocked on a full notify channel, while holding q.mu, stopped
+// a concurrent Shutdown from ever acquiring that same lock to begin
+// closing the queue. With the fix, Shutdown can always make progress
+// and honors its ctx even while a consumer is wedged and a Submit is
+// still blocked handing off to notify.
+func TestShutdownDoesNotBlockOnFullQueue(t *testing.T) {
+	q, err := New(newMemStorage(), Config{QueueSize: 1, NumConsumers: 1}, testLogger())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	block := make(chan struct{})
+	q.RegisterHandler("Slow", func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		<-block
+		return req, nil
+	})
+	if err := q.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	// The first Submit is picked up immediately by the lone consumer,
+	// which then wedges in the handler. The second fills notify's
+	// buffer (QueueSize: 1). The third has nowhere to go and blocks
+	// inside Submit until Shutdown (or a consumer) frees it.
+	for i := 0; i < 2; i++ {
+		if _, err := q.Submit(context.Background(), "Slow", &emptypb.Empty{}); err != nil {
+			t.Fatalf("Submit %d: %v", i, err)
+		}
+	}
+
+	blockedSubmitDone := make(chan struct{})
+	go func() {
+		defer close(blockedSubmitDone)
+		q.Submit(context.Background(), "Slow", &emptypb.Empty{})
+	}()
+	t.Cleanup(func() {
+		close(block)
+		<-blockedSubmitDone
+	})
+
+	// Give the third Submit a moment to actually start blocking on the
+	// full channel before asking the queue to shut down.
+	time.Sleep(50 * time.Millisecond)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	shutdownDone := make(chan error, 1)
+	go func() {
+		shutdownDone <- q.Shutdown(shutdownCtx)
+	}()
+
+	select {
+	case err := <-shutdownDone:
+		if err != context.DeadlineExceeded {
+			t.Errorf("Shutdown returned %v, want context.DeadlineExceeded (the wedged consumer never finishes)", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Shutdown did not return; a blocked Submit is still holding q.mu")
+	}
+}
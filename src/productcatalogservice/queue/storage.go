@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Storage is a durable key-value store for pending queue envelopes, keyed
+// by their monotonically increasing sequence number. Implementations
+// must make Put durable before returning, since that durability is what
+// lets the queue survive a process restart without dropping requests.
+type Storage interface {
+	Put(ctx context.Context, seq uint64, data []byte) error
+	Get(ctx context.Context, seq uint64) (data []byte, ok bool, err error)
+	Delete(ctx context.Context, seq uint64) error
+	// Scan invokes fn once per stored envelope, in ascending sequence
+	// order, so the caller can re-enqueue everything left over from a
+	// previous run before serving new traffic.
+	Scan(ctx context.Context, fn func(seq uint64, data []byte) error) error
+	Close() error
+}
+
+var bucketName = []byte("queue")
+
+// BoltStorage is the default Storage, backed by a single BoltDB file
+// under the directory named by PRODUCT_CATALOG_QUEUE_DIR.
+type BoltStorage struct {
+	db *bolt.DB
+}
+
+// NewBoltStorage opens (creating if necessary) a BoltDB-backed queue
+// store under dir.
+func NewBoltStorage(dir string) (*BoltStorage, error) {
+	db, err := bolt.Open(fmt.Sprintf("%s/queue.db", dir), 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("opening queue storage at %s: %w", dir, err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketName)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating queue bucket: %w", err)
+	}
+
+	return &BoltStorage{db: db}, nil
+}
+
+func seqKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
+
+func (s *BoltStorage) Put(_ context.Context, seq uint64, data []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).Put(seqKey(seq), data)
+	})
+}
+
+func (s *BoltStorage) Get(_ context.Context, seq uint64) ([]byte, bool, error) {
+	var data []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(bucketName).Get(seqKey(seq)); v != nil {
+			data = append([]byte(nil), v...)
+		}
+		return nil
+	})
+	return data, data != nil, err
+}
+
+func (s *BoltStorage) Delete(_ context.Context, seq uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).Delete(seqKey(seq))
+	})
+}
+
+func (s *BoltStorage) Scan(_ context.Context, fn func(seq uint64, data []byte) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).ForEach(func(k, v []byte) error {
+			return fn(binary.BigEndian.Uint64(k), append([]byte(nil), v...))
+		})
+	})
+}
+
+func (s *BoltStorage) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,156 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package search implements a tokenized inverted index with BM25 ranking
+// over the in-memory product catalog, replacing a naive substring scan.
+package search
+
+import (
+	"math"
+
+	pb "github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/genproto/oteldemo"
+)
+
+// DefaultK1 and DefaultB are Okapi BM25's usual defaults: k1 controls
+// term-frequency saturation, b controls how much document length is
+// penalized.
+const (
+	DefaultK1 = 1.2
+	DefaultB  = 0.75
+)
+
+// Result is one scored match.
+type Result struct {
+	Product *pb.Product
+	Score   float64
+}
+
+// Index is an immutable snapshot of the catalog's inverted index. A new
+// Index is built and atomically swapped in whenever the catalog changes;
+// readers never block on a rebuild.
+type Index struct {
+	k1, b float64
+
+	docs      []*pb.Product
+	docLen    []int
+	avgDocLen float64
+	postings  map[string][]posting
+}
+
+type posting struct {
+	doc int
+	tf  int
+}
+
+// Build tokenizes every product's name, description and categories and
+// returns a ready-to-query Index.
+func Build(products []*pb.Product, k1, b float64) *Index {
+	idx := &Index{
+		k1:       k1,
+		b:        b,
+		docs:     products,
+		docLen:   make([]int, len(products)),
+		postings: make(map[string][]posting),
+	}
+
+	var totalLen int
+	for docID, p := range products {
+		tokens := tokenize(p.Name + " " + p.Description + " " + joinCategories(p.Categories))
+		idx.docLen[docID] = len(tokens)
+		totalLen += len(tokens)
+
+		termFreq := make(map[string]int, len(tokens))
+		for _, tok := range tokens {
+			termFreq[tok]++
+		}
+		for term, tf := range termFreq {
+			idx.postings[term] = append(idx.postings[term], posting{doc: docID, tf: tf})
+		}
+	}
+
+	if len(products) > 0 {
+		idx.avgDocLen = float64(totalLen) / float64(len(products))
+	}
+
+	return idx
+}
+
+func joinCategories(categories []string) string {
+	out := ""
+	for i, c := range categories {
+		if i > 0 {
+			out += " "
+		}
+		out += c
+	}
+	return out
+}
+
+// Query ranks every document containing at least one query term by
+// BM25 score, optionally restricted to categories, and returns matches
+// with score >= minScore in descending score order.
+func (idx *Index) Query(query string, minScore float64, categories []string) []Result {
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	n := float64(len(idx.docs))
+	scores := make(map[int]float64)
+
+	for _, term := range terms {
+		postingsList := idx.postings[term]
+		if len(postingsList) == 0 {
+			continue
+		}
+		idf := math.Log(1 + (n-float64(len(postingsList))+0.5)/(float64(len(postingsList))+0.5))
+
+		for _, p := range postingsList {
+			tf := float64(p.tf)
+			norm := 1 - idx.b + idx.b*(float64(idx.docLen[p.doc])/idx.avgDocLenOrOne())
+			scores[p.doc] += idf * (tf * (idx.k1 + 1)) / (tf + idx.k1*norm)
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for doc, score := range scores {
+		if score < minScore {
+			continue
+		}
+		if len(categories) > 0 && !hasAnyCategory(idx.docs[doc], categories) {
+			continue
+		}
+		results = append(results, Result{Product: idx.docs[doc], Score: score})
+	}
+
+	sortByScoreDesc(results)
+	return results
+}
+
+func (idx *Index) avgDocLenOrOne() float64 {
+	if idx.avgDocLen == 0 {
+		return 1
+	}
+	return idx.avgDocLen
+}
+
+func hasAnyCategory(p *pb.Product, wanted []string) bool {
+	for _, c := range p.Categories {
+		for _, w := range wanted {
+			if c == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sortByScoreDesc(results []Result) {
+	// Results sets are small (catalog-sized), so a simple insertion sort
+	// keeps this dependency-free and is plenty fast.
+	for i := 1; i < len(results); i++ {
+		for j := i; j > 0 && results[j].Score > results[j-1].Score; j-- {
+			results[j], results[j-1] = results[j-1], results[j]
+		}
+	}
+}
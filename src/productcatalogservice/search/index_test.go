@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package search
+
+import (
+	"testing"
+
+	pb "github.com/opentelemetry/opentelemetry-demo/src/productcatalogservice/genproto/oteldemo"
+)
+
+func testProducts() []*pb.Product {
+	return []*pb.Product{
+		{Id: "1", Name: "Coffee Mug", Description: "A sturdy ceramic mug for your morning coffee", Categories: []string{"kitchen"}},
+		{Id: "2", Name: "Tea Kettle", Description: "Stovetop kettle for brewing tea", Categories: []string{"kitchen"}},
+		{Id: "3", Name: "Sunglasses", Description: "Polarized sunglasses for sunny days", Categories: []string{"accessories"}},
+	}
+}
+
+func TestIndexQueryRanksMatchingDocsByScore(t *testing.T) {
+	idx := Build(testProducts(), DefaultK1, DefaultB)
+
+	results := idx.Query("coffee mug", 0, nil)
+	if len(results) == 0 {
+		t.Fatal("Query(\"coffee mug\") returned no results")
+	}
+	if results[0].Product.Id != "1" {
+		t.Errorf("top result = product %s, want product 1", results[0].Product.Id)
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i].Score > results[i-1].Score {
+			t.Errorf("results not sorted by descending score: %v", results)
+		}
+	}
+}
+
+func TestIndexQueryNoMatchingTermsReturnsNoResults(t *testing.T) {
+	idx := Build(testProducts(), DefaultK1, DefaultB)
+
+	if results := idx.Query("umbrella", 0, nil); len(results) != 0 {
+		t.Errorf("Query(\"umbrella\") = %v, want no results", results)
+	}
+}
+
+func TestIndexQueryEmptyAfterStopWordsReturnsNil(t *testing.T) {
+	idx := Build(testProducts(), DefaultK1, DefaultB)
+
+	if results := idx.Query("the", 0, nil); results != nil {
+		t.Errorf("Query(\"the\") = %v, want nil", results)
+	}
+}
+
+func TestIndexQueryFiltersByCategory(t *testing.T) {
+	idx := Build(testProducts(), DefaultK1, DefaultB)
+
+	results := idx.Query("kitchen", 0, []string{"accessories"})
+	for _, r := range results {
+		if r.Product.Id == "1" || r.Product.Id == "2" {
+			t.Errorf("Query with categories=[accessories] returned kitchen product %s", r.Product.Id)
+		}
+	}
+}
+
+func TestIndexQueryMinScoreExcludesLowRelevanceMatches(t *testing.T) {
+	idx := Build(testProducts(), DefaultK1, DefaultB)
+
+	all := idx.Query("coffee mug", 0, nil)
+	if len(all) == 0 {
+		t.Fatal("expected at least one result to establish a score ceiling")
+	}
+	if results := idx.Query("coffee mug", all[0].Score+1, nil); len(results) != 0 {
+		t.Errorf("Query with minScore above every match's score returned %v, want none", results)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package search
+
+import (
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+)
+
+// QueryHash fingerprints a query string so a page token can be rejected
+// if it's replayed against a different query.
+func QueryHash(query string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(query))
+	return h.Sum64()
+}
+
+// EncodeToken builds an opaque page token for a query yielding more
+// results past offset.
+func EncodeToken(offset int, queryHash uint64) string {
+	raw := fmt.Sprintf("%d:%d", offset, queryHash)
+	return base64.URLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeToken parses a token produced by EncodeToken. It returns an
+// error if the token is malformed, which callers should surface as an
+// invalid-argument RPC error rather than silently resetting to page 1.
+func DecodeToken(token string) (offset int, queryHash uint64, err error) {
+	raw, err := base64.URLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, 0, fmt.Errorf("decoding page token: %w", err)
+	}
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &offset, &queryHash); err != nil {
+		return 0, 0, fmt.Errorf("parsing page token: %w", err)
+	}
+	return offset, queryHash, nil
+}
@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package search
+
+import "testing"
+
+func TestEncodeDecodeTokenRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		offset    int
+		queryHash uint64
+	}{
+		{"zero offset", 0, 12345},
+		{"nonzero offset", 42, 9999999999},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := EncodeToken(tt.offset, tt.queryHash)
+			gotOffset, gotHash, err := DecodeToken(token)
+			if err != nil {
+				t.Fatalf("DecodeToken(%q) returned error: %v", token, err)
+			}
+			if gotOffset != tt.offset || gotHash != tt.queryHash {
+				t.Errorf("DecodeToken(%q) = (%d, %d), want (%d, %d)", token, gotOffset, gotHash, tt.offset, tt.queryHash)
+			}
+		})
+	}
+}
+
+func TestDecodeTokenRejectsMalformedInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"not base64", "!!!not-base64!!!"},
+		{"base64 but not offset:hash", "aGVsbG8="}, // decodes to "hello"
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := DecodeToken(tt.token); err == nil {
+				t.Errorf("DecodeToken(%q) returned nil error, want one", tt.token)
+			}
+		})
+	}
+}
+
+func TestQueryHash(t *testing.T) {
+	if QueryHash("coffee mug") != QueryHash("coffee mug") {
+		t.Error("QueryHash is not deterministic for the same input")
+	}
+	if QueryHash("coffee mug") == QueryHash("tea kettle") {
+		t.Error("QueryHash collided for distinct queries")
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// stopWords is a short list of high-frequency English words that carry
+// no search signal for a product catalog this size; filtering them out
+// keeps postings lists small and avoids them dominating IDF.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "into": true,
+	"is": true, "it": true, "of": true, "on": true, "or": true, "our": true,
+	"that": true, "the": true, "this": true, "to": true, "with": true,
+}
+
+// tokenize splits text into lowercased, NFC-normalized, stop-word
+// filtered tokens, on runs of non-letter/non-digit characters.
+func tokenize(text string) []string {
+	text = norm.NFC.String(strings.ToLower(text))
+
+	var tokens []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() == 0 {
+			return
+		}
+		tok := current.String()
+		current.Reset()
+		if !stopWords[tok] {
+			tokens = append(tokens, tok)
+		}
+	}
+
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			current.WriteRune(r)
+		} else {
+			flush()
+		}
+	}
+	flush()
+
+	return tokens
+}
@@ -0,0 +1,32 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"lowercases and splits on punctuation", "Hot Coffee, Size: Large!", []string{"hot", "coffee", "size", "large"}},
+		{"drops stop words", "A mug for the coffee", []string{"mug", "coffee"}},
+		{"collapses runs of separators", "sun---glasses", []string{"sun", "glasses"}},
+		{"empty input yields no tokens", "", nil},
+		{"all stop words yields no tokens", "a the of", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tokenize(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenize(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
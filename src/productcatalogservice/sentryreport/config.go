@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sentryreport wires github.com/getsentry/sentry-go into
+// ProductCatalogService so that panics and logged errors are reported
+// with the active OpenTelemetry trace attached, instead of only
+// crashing the process or scrolling past in logs.
+package sentryreport
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sirupsen/logrus"
+)
+
+// Config controls Sentry initialization; see the SENTRY_* env vars read
+// by ConfigFromEnv.
+type Config struct {
+	DSN         string
+	Environment string
+	SampleRate  float64
+	// Repanic selects what UnaryServerInterceptor does once it has
+	// reported a panic to Sentry: re-panic (so the process crashes and
+	// is restarted, e.g. under a supervisor that expects that) or
+	// convert it to a codes.Internal error and keep serving.
+	Repanic bool
+	// FlushTimeout bounds how long Flush waits for queued events to be
+	// delivered during graceful shutdown.
+	FlushTimeout time.Duration
+}
+
+// ConfigFromEnv reads SENTRY_DSN, SENTRY_ENVIRONMENT, SENTRY_SAMPLE_RATE,
+// SENTRY_REPANIC and SENTRY_WAIT_FOR_DELIVERY_TIMEOUT. An empty
+// SENTRY_DSN disables reporting: Init then skips sentry.Init, so no
+// events are sent, though the Reporter's interceptor still recovers
+// panics as described on UnaryServerInterceptor.
+func ConfigFromEnv() Config {
+	return Config{
+		DSN:          os.Getenv("SENTRY_DSN"),
+		Environment:  os.Getenv("SENTRY_ENVIRONMENT"),
+		SampleRate:   envFloat("SENTRY_SAMPLE_RATE", 1.0),
+		Repanic:      envBool("SENTRY_REPANIC", false),
+		FlushTimeout: envDuration("SENTRY_WAIT_FOR_DELIVERY_TIMEOUT", 2*time.Second),
+	}
+}
+
+// Reporter bundles the Sentry client with the pieces of main.go's
+// lifecycle it needs to hook into: an interceptor to install on the
+// gRPC server, a logrus hook to install on the service's logger, and a
+// Flush to call during graceful shutdown, once srv.GracefulStop has
+// drained in-flight RPCs.
+type Reporter struct {
+	cfg Config
+}
+
+// Init configures the global Sentry hub from cfg. With an empty DSN,
+// sentry-go's client silently discards everything it's given, so the
+// returned Reporter's interceptor and hook are safe to install
+// unconditionally.
+func Init(cfg Config, log *logrus.Logger) (*Reporter, error) {
+	if cfg.DSN != "" {
+		if err := sentry.Init(sentry.ClientOptions{
+			Dsn:         cfg.DSN,
+			Environment: cfg.Environment,
+			SampleRate:  cfg.SampleRate,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return &Reporter{cfg: cfg}, nil
+}
+
+// Flush blocks until queued events are sent, or FlushTimeout elapses.
+func (r *Reporter) Flush() bool {
+	return sentry.Flush(r.cfg.FlushTimeout)
+}
+
+func envFloat(key string, fallback float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fallback
+	}
+	return f
+}
+
+func envBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fallback
+	}
+	return b
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sentryreport
+
+import (
+	"context"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor recovers panics from the wrapped handler,
+// reports them to Sentry with the active span's TraceID/SpanID attached
+// as tags so the event deep-links back to the trace, then either
+// re-panics (cfg.Repanic) or converts the panic into a codes.Internal
+// error so the server keeps serving.
+func (r *Reporter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+
+			hub := sentry.CurrentHub().Clone()
+			span := trace.SpanFromContext(ctx)
+			hub.Scope().SetTags(map[string]string{
+				"grpc.method": info.FullMethod,
+				"trace_id":    span.SpanContext().TraceID().String(),
+				"span_id":     span.SpanContext().SpanID().String(),
+			})
+			hub.RecoverWithContext(ctx, rec)
+
+			if r.cfg.Repanic {
+				panic(rec)
+			}
+			err = status.Errorf(codes.Internal, "panic in %s: %v", info.FullMethod, rec)
+		}()
+
+		return handler(ctx, req)
+	}
+}
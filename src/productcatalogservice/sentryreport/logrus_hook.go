@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sentryreport
+
+import (
+	"github.com/getsentry/sentry-go"
+	"github.com/sirupsen/logrus"
+)
+
+// LogrusHook forwards log entries at or above Level as Sentry
+// breadcrumbs, so an event reported later by UnaryServerInterceptor
+// carries the log lines that led up to it.
+type LogrusHook struct {
+	Level logrus.Level
+}
+
+// NewLogrusHook builds a hook that captures logrus.ErrorLevel and
+// above. Install it with log.AddHook(sentryreport.NewLogrusHook()).
+func NewLogrusHook() *LogrusHook {
+	return &LogrusHook{Level: logrus.ErrorLevel}
+}
+
+func (h *LogrusHook) Levels() []logrus.Level {
+	return logrus.AllLevels[:h.Level+1]
+}
+
+func (h *LogrusHook) Fire(entry *logrus.Entry) error {
+	data := make(map[string]any, len(entry.Data))
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+
+	sentry.CurrentHub().AddBreadcrumb(&sentry.Breadcrumb{
+		Category:  "log",
+		Message:   entry.Message,
+		Level:     breadcrumbLevel(entry.Level),
+		Data:      data,
+		Timestamp: entry.Time,
+	}, nil)
+	return nil
+}
+
+func breadcrumbLevel(level logrus.Level) sentry.Level {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		return sentry.LevelFatal
+	case logrus.ErrorLevel:
+		return sentry.LevelError
+	case logrus.WarnLevel:
+		return sentry.LevelWarning
+	default:
+		return sentry.LevelInfo
+	}
+}